@@ -0,0 +1,138 @@
+// +build !windows
+
+package hx711
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CalibrationStep is one point sampled by Calibrate. Weight is the known weight placed
+// on the scale; Ready is called right before sampling so the caller can wait for a
+// button press, a GUI confirmation, or just time.Sleep.
+type CalibrationStep struct {
+	Weight float64
+	Ready  func() error
+}
+
+// CalibrationResult is the outcome of Calibrate. Zero and Scale can be assigned straight
+// to Hx711.AdjustZero/AdjustScale (or to a Channel's Zero/Scale). R2 is the linear fit's
+// coefficient of determination, in [0, 1] for a sane calibration, so callers can detect
+// a bad one. Samples holds the raw reading taken for each step, in the same order.
+type CalibrationResult struct {
+	Zero    int
+	Scale   float64
+	R2      float64
+	Samples []int
+}
+
+// Calibrate samples a median raw reading for each of steps, in order, calling its Ready
+// callback first, then fits raw = Zero + Scale*weight by linear regression across all of
+// them. At least 2 steps are required, but more give a more robust fit than the
+// two-point calibration GetAdjustValues does.
+// Do not call Reset before or Shutdown after. Reset and Shutdown are called for you.
+func (hx711 *Hx711) Calibrate(steps []CalibrationStep) (CalibrationResult, error) {
+	if len(steps) < 2 {
+		return CalibrationResult{}, fmt.Errorf("Calibrate needs at least 2 steps, got %d", len(steps))
+	}
+
+	if err := hx711.Reset(); err != nil {
+		return CalibrationResult{}, fmt.Errorf("Reset error: %w", err)
+	}
+	defer hx711.Shutdown()
+
+	samples := make([]int, len(steps))
+	for i, step := range steps {
+		if step.Ready != nil {
+			if err := step.Ready(); err != nil {
+				return CalibrationResult{}, fmt.Errorf("Ready error for step %d: %w", i, err)
+			}
+		}
+
+		data, err := hx711.ReadDataMedianRaw(11)
+		if err != nil {
+			return CalibrationResult{}, fmt.Errorf("ReadDataMedianRaw error for step %d: %w", i, err)
+		}
+		samples[i] = data
+	}
+
+	zero, scale, r2 := fitLinear(steps, samples)
+
+	return CalibrationResult{Zero: zero, Scale: scale, R2: r2, Samples: samples}, nil
+}
+
+// fitLinear fits raw = zero + scale*weight across steps/samples by least squares and
+// returns the coefficient of determination alongside it. zero is rounded to the nearest
+// raw count since Hx711.AdjustZero is an int.
+func fitLinear(steps []CalibrationStep, samples []int) (zero int, scale float64, r2 float64) {
+	n := float64(len(steps))
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, step := range steps {
+		x, y := step.Weight, float64(samples[i])
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	meanX := sumX / n
+	meanY := sumY / n
+
+	denom := sumXX - n*meanX*meanX
+	if denom == 0 {
+		// every step used the same weight: no slope can be fit, fall back to the mean.
+		return int(math.Round(meanY)), 0, 0
+	}
+
+	slope := (sumXY - n*meanX*meanY) / denom
+	intercept := meanY - slope*meanX
+
+	var ssRes, ssTot float64
+	for i, step := range steps {
+		y := float64(samples[i])
+		residual := y - (intercept + slope*step.Weight)
+		ssRes += residual * residual
+		ssTot += (y - meanY) * (y - meanY)
+	}
+
+	if ssTot == 0 {
+		r2 = 1
+	} else {
+		r2 = 1 - ssRes/ssTot
+	}
+
+	return int(math.Round(intercept)), slope, r2
+}
+
+// calibrationJSON is the on-disk representation written by SaveCalibration and read by
+// LoadCalibration.
+type calibrationJSON struct {
+	AdjustZero  int     `json:"adjust_zero"`
+	AdjustScale float64 `json:"adjust_scale"`
+}
+
+// SaveCalibration writes hx711.AdjustZero and hx711.AdjustScale to w as JSON, so they
+// can be restored with LoadCalibration instead of every program re-running a
+// calibration or hard-coding the values.
+func (hx711 *Hx711) SaveCalibration(w io.Writer) error {
+	return json.NewEncoder(w).Encode(calibrationJSON{
+		AdjustZero:  hx711.AdjustZero,
+		AdjustScale: hx711.AdjustScale,
+	})
+}
+
+// LoadCalibration reads AdjustZero/AdjustScale JSON previously written by
+// SaveCalibration from r and applies it to hx711.
+func (hx711 *Hx711) LoadCalibration(r io.Reader) error {
+	var c calibrationJSON
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return fmt.Errorf("decode calibration error: %w", err)
+	}
+
+	hx711.AdjustZero = c.AdjustZero
+	hx711.AdjustScale = c.AdjustScale
+	return nil
+}
@@ -0,0 +1,42 @@
+// +build !windows
+
+package hx711
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitLinear(t *testing.T) {
+	steps := []CalibrationStep{{Weight: 0}, {Weight: 1}, {Weight: 2}}
+	samples := []int{1000, 2000, 3000}
+
+	zero, scale, r2 := fitLinear(steps, samples)
+
+	if zero != 1000 {
+		t.Errorf("zero = %d, want 1000", zero)
+	}
+	if math.Abs(scale-1000) > 1e-9 {
+		t.Errorf("scale = %v, want 1000", scale)
+	}
+	if math.Abs(r2-1) > 1e-9 {
+		t.Errorf("r2 = %v, want 1", r2)
+	}
+}
+
+func TestFitLinearDegenerate(t *testing.T) {
+	steps := []CalibrationStep{{Weight: 5}, {Weight: 5}, {Weight: 5}}
+	samples := []int{100, 200, 300}
+
+	zero, scale, r2 := fitLinear(steps, samples)
+
+	if zero != 200 {
+		t.Errorf("zero = %d, want 200 (mean of samples)", zero)
+	}
+	if scale != 0 {
+		t.Errorf("scale = %v, want 0", scale)
+	}
+	if r2 != 0 {
+		t.Errorf("r2 = %v, want 0", r2)
+	}
+}
@@ -0,0 +1,113 @@
+// +build !windows
+
+package hx711
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Channel is one of the HX711's two analog inputs, selected by SetGain: channel A
+// supports gain 128 or 64, channel B is fixed at gain 32. Zero and Scale hold that
+// channel's own calibration, since the two channels are typically driven by different
+// load cells and cannot share a single Hx711.AdjustZero/AdjustScale pair. Calibrate it
+// the same way as Hx711.AdjustZero/AdjustScale: call hx711.SetGain(ch.Gain), run
+// Calibrate, and assign its Zero/Scale result to the Channel.
+type Channel struct {
+	ID    string
+	Gain  int
+	Zero  int
+	Scale float64
+}
+
+// selectChannel switches the gain to ch's. The datasheet only applies a new gain on the
+// conversion that follows the switch; SetGain already discards that first reading for us
+// via applyGain, so callers can read immediately after selectChannel returns.
+func (hx711 *Hx711) selectChannel(ch Channel) error {
+	if err := hx711.SetGain(ch.Gain); err != nil {
+		return fmt.Errorf("SetGain error for channel %q: %w", ch.ID, err)
+	}
+	return nil
+}
+
+// ReadChannels reads numReadings raw samples from each of chans in turn, switching gain
+// between them, and adjusts each result with that channel's own Zero and Scale. The
+// result is keyed by Channel.ID rather than the Channel value itself, since Zero/Scale
+// are expected to change as a channel gets (re)calibrated and a struct key would fork
+// silently the moment they do.
+// ReadChannels does not call Reset or Shutdown itself.
+func (hx711 *Hx711) ReadChannels(ctx context.Context, numReadings int, chans ...Channel) (map[string]float64, error) {
+	results := make(map[string]float64, len(chans))
+
+	for _, ch := range chans {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err := hx711.selectChannel(ch); err != nil {
+			return nil, err
+		}
+
+		data, err := hx711.readDataMedianRaw(ctx, numReadings)
+		if err != nil {
+			return nil, fmt.Errorf("read error for channel %q: %w", ch.ID, err)
+		}
+
+		results[ch.ID] = float64(data-ch.Zero) / ch.Scale
+	}
+
+	return results, nil
+}
+
+// BackgroundReadChannelsCtx is meant to be run in the background, as a goroutine. It
+// cycles through a snapshot of chans taken up front, switching gain between them, and
+// updates values[ch.ID] with each channel's latest reading until ctx is done, then
+// returns ctx.Err(). The snapshot means a caller is free to keep recalibrating its own
+// chans slice (e.g. assigning a fresh Calibrate result to chans[i].Zero/Scale) without
+// racing this goroutine's reads of it; restart the goroutine to pick up new calibration.
+// Reads for different channels are interleaved rather than batched, since only one
+// channel's gain can be selected on the hardware at a time.
+// Do not call Reset before or Shutdown after; Reset and Shutdown are called for you.
+// Will panic if values is nil or chans is empty.
+func (hx711 *Hx711) BackgroundReadChannelsCtx(ctx context.Context, numReadings int, chans []Channel, values map[string]float64) error {
+	snapshot := make([]Channel, len(chans))
+	copy(snapshot, chans)
+
+	for {
+		err := hx711.Reset()
+		if err == nil {
+			break
+		}
+		hx711.logger().Errorf("BackgroundReadChannelsCtx Reset error: %v", err)
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for i := 0; ctx.Err() == nil; i = (i + 1) % len(snapshot) {
+		ch := snapshot[i]
+
+		if err := hx711.selectChannel(ch); err != nil {
+			hx711.logger().Errorf("BackgroundReadChannelsCtx %v", err)
+			continue
+		}
+
+		data, err := hx711.readDataMedianRaw(ctx, numReadings)
+		if err != nil {
+			if ctx.Err() == nil {
+				hx711.logger().Errorf("BackgroundReadChannelsCtx read error for channel %q: %v", ch.ID, err)
+			}
+			continue
+		}
+
+		values[ch.ID] = float64(data-ch.Zero) / ch.Scale
+	}
+
+	hx711.Shutdown()
+
+	return ctx.Err()
+}
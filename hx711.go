@@ -3,8 +3,10 @@
 package hx711
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"runtime"
+	"runtime/debug"
 	"sort"
 	"time"
 
@@ -13,6 +15,36 @@ import (
 	"periph.io/x/periph/host"
 )
 
+// Hx711 holds the state needed to communicate with a HX711 load cell amplifier.
+// AdjustZero and AdjustScale are used to convert a raw reading into a weight.
+type Hx711 struct {
+	clockPin gpio.PinIO
+	dataPin  gpio.PinIO
+
+	numEndPulses int
+
+	AdjustZero  int
+	AdjustScale float64
+
+	// RealtimeMode locks ReadDataRaw to its OS thread and briefly disables the garbage
+	// collector while bit-banging the clock pin, so a GC pause or goroutine migration
+	// can't hold the clock high long enough for the chip to enter power-down. It trades
+	// a little GC latency for fewer "clock was high for too long" retries under load.
+	RealtimeMode bool
+
+	// Logger receives diagnostic messages from BackgroundReadMovingAvgs,
+	// BackgroundReadChannelsCtx and GetAdjustValues.
+	// A nil Logger (the default) discards them.
+	Logger Logger
+
+	mode       ReadMode
+	modeConfig modeConfig
+
+	hasRunningValue bool
+	runningValue    float64
+	movingWindow    []float64
+}
+
 // HostInit calls periph.io host.Init(). This needs to be done before Hx711 can be used.
 func HostInit() error {
 	_, err := host.Init()
@@ -172,6 +204,38 @@ func (hx711 *Hx711) ReadDataRaw() (int, error) {
 		return 0, fmt.Errorf("waitForDataReady error: %w", err)
 	}
 
+	data, err := hx711.readPulses()
+	if err != nil {
+		return 0, err
+	}
+
+	// if high 24 bit is set, value is negtive
+	// 100000000000000000000000
+	if (data & 0x800000) > 0 {
+		// flip bits 24 and lower to get negtive number for int
+		// 111111111111111111111111
+		data |= ^0xffffff
+	}
+
+	return data, nil
+}
+
+// readPulses clocks out the 24 data bits plus the gain-select end pulses, the ~1ms
+// bit-banging window during which the clock line must never stay high for more than
+// 60µs. If RealtimeMode is set, the goroutine is locked to its OS thread and the garbage
+// collector is disabled for just this window, restoring both before returning, so a GC
+// pause or goroutine migration can't hold the clock high long enough for the chip to
+// enter power-down.
+func (hx711 *Hx711) readPulses() (int, error) {
+	if hx711.RealtimeMode {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		gcPercent := debug.SetGCPercent(-1)
+		defer debug.SetGCPercent(gcPercent)
+	}
+
+	var err error
 	var level gpio.Level
 	var data int
 	for i := 0; i < 24; i++ {
@@ -196,26 +260,19 @@ func (hx711 *Hx711) ReadDataRaw() (int, error) {
 		}
 	}
 
-	// if high 24 bit is set, value is negtive
-	// 100000000000000000000000
-	if (data & 0x800000) > 0 {
-		// flip bits 24 and lower to get negtive number for int
-		// 111111111111111111111111
-		data |= ^0xffffff
-	}
-
 	return data, nil
 }
 
-// readDataMedianRaw will get median of numReadings raw readings.
-func (hx711 *Hx711) readDataMedianRaw(numReadings int, stop *bool) (int, error) {
+// readDataMedianRaw will get median of numReadings raw readings, aborting early if ctx
+// is cancelled.
+func (hx711 *Hx711) readDataMedianRaw(ctx context.Context, numReadings int) (int, error) {
 	var err error
 	var data int
 	datas := make([]int, 0, numReadings)
 
 	for i := 0; i < numReadings; i++ {
-		if *stop {
-			return 0, fmt.Errorf("stopped")
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
 		}
 
 		data, err = hx711.ReadDataRaw()
@@ -249,8 +306,7 @@ func (hx711 *Hx711) ReadDataMedianRaw(numReadings int) (int, error) {
 	// 	return 0, fmt.Errorf("Reset error: %v", err)
 	// }
 
-	stop := false
-	data, err := hx711.readDataMedianRaw(numReadings, &stop)
+	data, err := hx711.readDataMedianRaw(context.Background(), numReadings)
 
 	// hx711.Shutdown()
 
@@ -274,16 +330,24 @@ func (hx711 *Hx711) ReadDataMedian(numReadings int) (float64, error) {
 // then will adjust number with AdjustZero and AdjustScale.
 // Do not call Reset before or Shutdown after.
 // Reset and Shutdown are called for you.
+// This is the same strategy as ModeAverage.
 func (hx711 *Hx711) ReadDataMedianThenAvg(numReadings, numAvgs int) (float64, error) {
-	var sum int
-	for i := 0; i < numAvgs; i++ {
-		data, err := hx711.ReadDataMedianRaw(numReadings)
-		if err != nil {
-			return 0, err
-		}
-		sum += data - hx711.AdjustZero
+	_, weight, err := hx711.readDataMedianThenAvg(context.Background(), numReadings, numAvgs)
+	return weight, err
+}
+
+// readDataMedianThenMovingAvgs is the shared implementation behind ReadDataMedianThenMovingAvgs
+// and BackgroundReadMovingAvgsCtx. ctx lets a background caller abort a reading in progress.
+// This is the same sliding-window strategy as ModeMovingAverage, but over a window owned
+// by the caller instead of the Hx711 itself.
+func (hx711 *Hx711) readDataMedianThenMovingAvgs(ctx context.Context, numReadings, numAvgs int, previousReadings *[]float64) (float64, error) {
+	data, err := hx711.readDataMedianRaw(ctx, numReadings)
+	if err != nil {
+		return 0, err
 	}
-	return (float64(sum) / float64(numAvgs)) / hx711.AdjustScale, nil
+
+	result := float64(data-hx711.AdjustZero) / hx711.AdjustScale
+	return slideWindow(previousReadings, numAvgs, result), nil
 }
 
 // ReadDataMedianThenMovingAvgs will get median of numReadings raw readings,
@@ -293,74 +357,81 @@ func (hx711 *Hx711) ReadDataMedianThenAvg(numReadings, numAvgs int) (float64, er
 // Reset and Shutdown are called for you.
 // Will panic if previousReadings is nil
 func (hx711 *Hx711) ReadDataMedianThenMovingAvgs(numReadings, numAvgs int, previousReadings *[]float64) (float64, error) {
-	data, err := hx711.ReadDataMedian(numReadings)
-	if err != nil {
-		return 0, err
-	}
-
-	if len(*previousReadings) < numAvgs {
-		*previousReadings = append(*previousReadings, data)
-	} else {
-		*previousReadings = append((*previousReadings)[1:numAvgs], data)
-	}
-
-	var result float64
-	for i := range *previousReadings {
-		result += (*previousReadings)[i]
-	}
-	return result / float64(len(*previousReadings)), nil
+	return hx711.readDataMedianThenMovingAvgs(context.Background(), numReadings, numAvgs, previousReadings)
 }
 
-// BackgroundReadMovingAvgs it meant to be run in the background, run as a Goroutine.
-// Will continue to get readings and update movingAvg until stop is set to true.
-// After it has been stopped, the stopped chan will be closed.
+// BackgroundReadMovingAvgsCtx is meant to be run in the background, as a goroutine.
+// Will continue to get readings and update movingAvg until ctx is done, then returns ctx.Err().
+// It uses the same sliding-window strategy as ModeMovingAverage, over a window private to
+// this call rather than one shared with Read/Stream's SetMode state.
 // Note when scale errors the movingAvg value will not change.
 // Do not call Reset before or Shutdown after.
 // Reset and Shutdown are called for you.
-// Will panic if movingAvg or stop are nil
-func (hx711 *Hx711) BackgroundReadMovingAvgs(numReadings, numAvgs int, movingAvg *float64, stop *bool, stopped chan struct{}) {
-	var err error
-	var data int
-	var result float64
+// Will panic if movingAvg is nil
+func (hx711 *Hx711) BackgroundReadMovingAvgsCtx(ctx context.Context, numReadings, numAvgs int, movingAvg *float64) error {
 	previousReadings := make([]float64, 0, numAvgs)
 
 	for {
-		err = hx711.Reset()
+		err := hx711.Reset()
 		if err == nil {
 			break
 		}
-		log.Print("hx711 BackgroundReadMovingAvgs Reset error:", err)
-		time.Sleep(time.Second)
-	}
+		hx711.logger().Errorf("BackgroundReadMovingAvgsCtx Reset error: %v", err)
 
-	for !*stop {
-		data, err = hx711.readDataMedianRaw(numReadings, stop)
-		if err != nil && err.Error() != "stopped" {
-			log.Print("hx711 BackgroundReadMovingAvgs ReadDataMedian error:", err)
-			continue
-		}
-
-		result = float64(data-hx711.AdjustZero) / hx711.AdjustScale
-		if len(previousReadings) < numAvgs {
-			previousReadings = append(previousReadings, result)
-		} else {
-			previousReadings = append(previousReadings[1:numAvgs], result)
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
 
-		result = 0
-		for i := range previousReadings {
-			result += previousReadings[i]
+	for ctx.Err() == nil {
+		result, err := hx711.readDataMedianThenMovingAvgs(ctx, numReadings, numAvgs, &previousReadings)
+		if err != nil {
+			if ctx.Err() == nil {
+				hx711.logger().Errorf("BackgroundReadMovingAvgsCtx ReadDataMedian error: %v", err)
+			}
+			continue
 		}
 
-		*movingAvg = result / float64(len(previousReadings))
+		*movingAvg = result
 	}
 
 	hx711.Shutdown()
 
+	return ctx.Err()
+}
+
+// BackgroundReadMovingAvgs it meant to be run in the background, run as a Goroutine.
+// Will continue to get readings and update movingAvg until stop is set to true.
+// After it has been stopped, the stopped chan will be closed.
+// It is a thin wrapper around BackgroundReadMovingAvgsCtx kept for backwards compatibility;
+// new code should prefer BackgroundReadMovingAvgsCtx or Stream.
+// Note when scale errors the movingAvg value will not change.
+// Do not call Reset before or Shutdown after.
+// Reset and Shutdown are called for you.
+// Will panic if movingAvg or stop are nil
+func (hx711 *Hx711) BackgroundReadMovingAvgs(numReadings, numAvgs int, movingAvg *float64, stop *bool, stopped chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		hx711.BackgroundReadMovingAvgsCtx(ctx, numReadings, numAvgs, movingAvg)
+		close(done)
+	}()
+
+	for !*stop {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
 	close(stopped)
 }
 
 // GetAdjustValues will help get you the adjust values to plug in later.
+// Progress and results are reported through hx711.Logger instead of stdout.
 // Do not call Reset before or Shutdown after.
 // Reset and Shutdown are called for you.
 func (hx711 *Hx711) GetAdjustValues(weight1 float64, weight2 float64) {
@@ -369,43 +440,39 @@ func (hx711 *Hx711) GetAdjustValues(weight1 float64, weight2 float64) {
 	var scale1 int
 	var scale2 int
 
-	fmt.Println("Make sure scale is working and empty, getting weight in 5 seconds...")
+	hx711.logger().Infof("Make sure scale is working and empty, getting weight in 5 seconds...")
 	time.Sleep(5 * time.Second)
-	fmt.Println("Getting weight...")
+	hx711.logger().Infof("Getting weight...")
 	adjustZero, err = hx711.ReadDataMedianRaw(11)
 	if err != nil {
-		fmt.Println("ReadDataMedianRaw error:", err)
+		hx711.logger().Errorf("ReadDataMedianRaw error: %v", err)
 		return
 	}
-	fmt.Println("Raw weight is:", adjustZero)
-	fmt.Println("")
+	hx711.logger().Infof("Raw weight is: %d", adjustZero)
 
-	fmt.Printf("Put first weight of %.2f on scale, getting weight in 15 seconds...\n", weight1)
+	hx711.logger().Infof("Put first weight of %.2f on scale, getting weight in 15 seconds...", weight1)
 	time.Sleep(15 * time.Second)
-	fmt.Println("Getting weight...")
+	hx711.logger().Infof("Getting weight...")
 	scale1, err = hx711.ReadDataMedianRaw(11)
 	if err != nil {
-		fmt.Println("ReadDataMedianRaw error:", err)
+		hx711.logger().Errorf("ReadDataMedianRaw error: %v", err)
 		return
 	}
-	fmt.Println("Raw weight is:", scale1)
-	fmt.Println("")
+	hx711.logger().Infof("Raw weight is: %d", scale1)
 
-	fmt.Printf("Put second weight of %.2f on scale, getting weight in 15 seconds...\n", weight2)
+	hx711.logger().Infof("Put second weight of %.2f on scale, getting weight in 15 seconds...", weight2)
 	time.Sleep(15 * time.Second)
-	fmt.Println("Getting weight...")
+	hx711.logger().Infof("Getting weight...")
 	scale2, err = hx711.ReadDataMedianRaw(11)
 	if err != nil {
-		fmt.Println("ReadDataMedianRaw error:", err)
+		hx711.logger().Errorf("ReadDataMedianRaw error: %v", err)
 		return
 	}
-	fmt.Println("Raw weight is ", scale2)
-	fmt.Println("")
+	hx711.logger().Infof("Raw weight is: %d", scale2)
 
 	adjust1 := float64(scale1-adjustZero) / weight1
 	adjust2 := float64(scale2-adjustZero) / weight2
 
-	fmt.Println("AdjustZero should be set to:", adjustZero)
-	fmt.Printf("AdjustScale should be set to a value between %f and %f\n", adjust1, adjust2)
-	fmt.Println("")
+	hx711.logger().Infof("AdjustZero should be set to: %d", adjustZero)
+	hx711.logger().Infof("AdjustScale should be set to a value between %f and %f", adjust1, adjust2)
 }
@@ -0,0 +1,29 @@
+// +build !windows
+
+package hx711
+
+// Logger is a minimal leveled logging interface. Hx711 routes its diagnostic messages
+// through it instead of the standard log package, so callers can forward them into
+// zap, zerolog, slog, or any other structured logger, or silence them entirely.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// noopLogger is the default Logger: it discards every message.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Infof(format string, args ...interface{})  {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
+func (noopLogger) Errorf(format string, args ...interface{}) {}
+
+// logger returns hx711.Logger, falling back to a no-op so callers never need a nil check.
+func (hx711 *Hx711) logger() Logger {
+	if hx711.Logger == nil {
+		return noopLogger{}
+	}
+	return hx711.Logger
+}
@@ -0,0 +1,226 @@
+// +build !windows
+
+package hx711
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ReadMode selects the sampling strategy used by Read and Stream.
+type ReadMode int
+
+const (
+	// ModeMedian takes the median of n raw samples. This is the default mode.
+	ModeMedian ReadMode = iota
+	// ModeAverage takes the median of n raw samples, NumAvgs times, then averages those medians.
+	ModeAverage
+	// ModeMedMax discards the highest and lowest of n raw samples and averages the remainder,
+	// the way Rob Tillaart's HX711 library rejects spikes. n must be at least 3.
+	ModeMedMax
+	// ModeRunningAverage keeps an exponentially-weighted running average across successive
+	// calls to Read or samples from Stream, smoothed by Alpha, so callers no longer need to
+	// manage their own previousReadings slice.
+	ModeRunningAverage
+	// ModeMovingAverage keeps a sliding window of the last NumAvgs medians and returns
+	// their average. It is the same strategy ReadDataMedianThenMovingAvgs and
+	// BackgroundReadMovingAvgs use, but with the window owned by the Hx711 instead of the
+	// caller.
+	ModeMovingAverage
+)
+
+// modeConfig holds the tunables for the currently selected ReadMode.
+type modeConfig struct {
+	numAvgs int
+	alpha   float64
+}
+
+// defaultModeConfig returns the modeConfig used when SetMode is called without options.
+func defaultModeConfig() modeConfig {
+	return modeConfig{numAvgs: 3, alpha: 0.2}
+}
+
+// ModeOption configures the ReadMode set by SetMode.
+type ModeOption func(*modeConfig)
+
+// WithNumAvgs sets how many medians ModeAverage averages together, or the sliding
+// window size for ModeMovingAverage. Default is 3.
+func WithNumAvgs(numAvgs int) ModeOption {
+	return func(c *modeConfig) { c.numAvgs = numAvgs }
+}
+
+// WithAlpha sets the smoothing factor used by ModeRunningAverage, in the range (0, 1].
+// Smaller values smooth more but react slower to real changes. Default is 0.2.
+func WithAlpha(alpha float64) ModeOption {
+	return func(c *modeConfig) { c.alpha = alpha }
+}
+
+// SetMode selects the strategy that Read and Stream use, and resets any running state it
+// keeps, such as the ModeRunningAverage value.
+func (hx711 *Hx711) SetMode(mode ReadMode, opts ...ModeOption) {
+	cfg := defaultModeConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	hx711.mode = mode
+	hx711.modeConfig = cfg
+	hx711.hasRunningValue = false
+	hx711.movingWindow = nil
+}
+
+// Read takes one weight reading using the ReadMode last selected with SetMode
+// (ModeMedian is used if SetMode was never called). n is the number of raw samples
+// combined into the reading; for ModeMedMax it must be at least 3.
+// Read does not call Reset or Shutdown itself.
+func (hx711 *Hx711) Read(n int) (float64, error) {
+	_, weight, err := hx711.readWithMode(context.Background(), n)
+	return weight, err
+}
+
+// readWithMode is the context-cancellable core behind Read and Stream: it dispatches to
+// the ReadMode last selected with SetMode and returns both the representative raw sample
+// and the adjusted weight, so Stream can populate Sample.Raw without bypassing the mode.
+func (hx711 *Hx711) readWithMode(ctx context.Context, n int) (raw int, weight float64, err error) {
+	switch hx711.mode {
+	case ModeAverage:
+		return hx711.readDataMedianThenAvg(ctx, n, hx711.modeConfig.numAvgs)
+	case ModeMedMax:
+		return hx711.readDataMedMax(ctx, n)
+	case ModeRunningAverage:
+		return hx711.readDataRunningAverage(ctx, n)
+	case ModeMovingAverage:
+		return hx711.readDataMovingAverage(ctx, n)
+	default:
+		data, err := hx711.readDataMedianRaw(ctx, n)
+		if err != nil {
+			return 0, 0, err
+		}
+		return data, float64(data-hx711.AdjustZero) / hx711.AdjustScale, nil
+	}
+}
+
+// readDataMedianThenAvg gets the median of numReadings raw samples, numAvgs times, and
+// averages those medians, aborting early if ctx is cancelled. raw is the most recent
+// median sampled.
+func (hx711 *Hx711) readDataMedianThenAvg(ctx context.Context, numReadings, numAvgs int) (raw int, weight float64, err error) {
+	var sum int
+	for i := 0; i < numAvgs; i++ {
+		if ctx.Err() != nil {
+			return 0, 0, ctx.Err()
+		}
+
+		data, err := hx711.readDataMedianRaw(ctx, numReadings)
+		if err != nil {
+			return 0, 0, err
+		}
+		raw = data
+		sum += data - hx711.AdjustZero
+	}
+
+	return raw, (float64(sum) / float64(numAvgs)) / hx711.AdjustScale, nil
+}
+
+// readDataMedMax takes n raw samples, discards the highest and lowest, and averages what
+// remains before adjusting with AdjustZero and AdjustScale, aborting early if ctx is
+// cancelled. raw is that average, rounded to the nearest raw count.
+func (hx711 *Hx711) readDataMedMax(ctx context.Context, n int) (raw int, weight float64, err error) {
+	if n < 3 {
+		return 0, 0, fmt.Errorf("ModeMedMax needs n >= 3, got %d", n)
+	}
+
+	var data int
+	datas := make([]int, 0, n)
+
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			return 0, 0, ctx.Err()
+		}
+
+		data, err = hx711.ReadDataRaw()
+		if err != nil {
+			continue
+		}
+		// reading of -1 seems to be some kind of error
+		if data == -1 {
+			continue
+		}
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 3 {
+		return 0, 0, fmt.Errorf("no data, last err: %w", err)
+	}
+
+	avg := trimExtremesAverage(datas)
+	return int(math.Round(avg)), (avg - float64(hx711.AdjustZero)) / hx711.AdjustScale, nil
+}
+
+// trimExtremesAverage sorts datas, discards its highest and lowest value, and returns the
+// average of what remains. datas must have at least 3 elements; it is sorted in place.
+func trimExtremesAverage(datas []int) float64 {
+	sort.Ints(datas)
+	datas = datas[1 : len(datas)-1]
+
+	var sum int
+	for _, d := range datas {
+		sum += d
+	}
+
+	return float64(sum) / float64(len(datas))
+}
+
+// readDataRunningAverage takes one median reading of n samples and folds it into an
+// exponentially-weighted running value, seeding it with the first reading. raw is the
+// underlying median sample, not the smoothed value.
+func (hx711 *Hx711) readDataRunningAverage(ctx context.Context, n int) (raw int, weight float64, err error) {
+	data, err := hx711.readDataMedianRaw(ctx, n)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reading := float64(data-hx711.AdjustZero) / hx711.AdjustScale
+	if !hx711.hasRunningValue {
+		hx711.runningValue = reading
+		hx711.hasRunningValue = true
+	} else {
+		hx711.runningValue += hx711.modeConfig.alpha * (reading - hx711.runningValue)
+	}
+
+	return data, hx711.runningValue, nil
+}
+
+// readDataMovingAverage takes one median reading of n samples and folds it into
+// hx711's sliding window of the last NumAvgs readings, returning their average.
+func (hx711 *Hx711) readDataMovingAverage(ctx context.Context, n int) (raw int, weight float64, err error) {
+	data, err := hx711.readDataMedianRaw(ctx, n)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result := float64(data-hx711.AdjustZero) / hx711.AdjustScale
+	return data, slideWindow(&hx711.movingWindow, hx711.modeConfig.numAvgs, result), nil
+}
+
+// slideWindow appends value to window, evicting the oldest entry once it already holds
+// numAvgs values, and returns the average of everything currently in it. This is the
+// shared sliding-window average behind ReadDataMedianThenMovingAvgs,
+// BackgroundReadMovingAvgs, and ModeMovingAverage. numAvgs below 1 is treated as 1.
+func slideWindow(window *[]float64, numAvgs int, value float64) float64 {
+	if numAvgs < 1 {
+		numAvgs = 1
+	}
+
+	if len(*window) < numAvgs {
+		*window = append(*window, value)
+	} else {
+		*window = append((*window)[1:numAvgs], value)
+	}
+
+	var sum float64
+	for _, v := range *window {
+		sum += v
+	}
+	return sum / float64(len(*window))
+}
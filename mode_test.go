@@ -0,0 +1,47 @@
+// +build !windows
+
+package hx711
+
+import "testing"
+
+func TestSlideWindow(t *testing.T) {
+	var window []float64
+
+	if avg := slideWindow(&window, 3, 1); avg != 1 {
+		t.Errorf("avg = %v, want 1", avg)
+	}
+	if avg := slideWindow(&window, 3, 3); avg != 2 {
+		t.Errorf("avg = %v, want 2", avg)
+	}
+	if avg := slideWindow(&window, 3, 5); avg != 3 {
+		t.Errorf("avg = %v, want 3", avg)
+	}
+	// window is now full; the oldest value (1) should be evicted.
+	if avg := slideWindow(&window, 3, 7); avg != 5 {
+		t.Errorf("avg = %v, want 5", avg)
+	}
+}
+
+func TestSlideWindowDegenerateNumAvgs(t *testing.T) {
+	var window []float64
+
+	if avg := slideWindow(&window, 0, 4); avg != 4 {
+		t.Errorf("avg = %v, want 4", avg)
+	}
+	if avg := slideWindow(&window, 0, 8); avg != 8 {
+		t.Errorf("avg = %v, want 8 (numAvgs<1 clamps to a window of 1)", avg)
+	}
+}
+
+func TestTrimExtremesAverage(t *testing.T) {
+	// sorted: 1, 2, 3, 10, 100 -> trim 1 and 100 -> average of 2, 3, 10
+	if avg := trimExtremesAverage([]int{10, 1, 2, 3, 100}); avg != 5 {
+		t.Errorf("avg = %v, want 5", avg)
+	}
+}
+
+func TestTrimExtremesAverageMinimalInput(t *testing.T) {
+	if avg := trimExtremesAverage([]int{1, 2, 3}); avg != 2 {
+		t.Errorf("avg = %v, want 2", avg)
+	}
+}
@@ -0,0 +1,94 @@
+// +build !windows
+
+package hx711
+
+import (
+	"context"
+	"time"
+)
+
+// Sample is one reading emitted by Stream.
+type Sample struct {
+	Raw       int
+	Weight    float64
+	Timestamp time.Time
+}
+
+// StreamOptions configures Stream.
+type StreamOptions struct {
+	// NumReadings is the number of raw samples combined into each Sample, passed through
+	// to Read exactly like its n parameter. Defaults to 11.
+	NumReadings int
+}
+
+// Stream continuously reads the scale, using the ReadMode last selected with SetMode,
+// and emits a Sample on the returned channel for every reading until ctx is cancelled.
+// Errors from individual readings are sent on the error channel; the loop keeps going
+// after one. Both channels are closed once the background goroutine exits. Consumers
+// can select on samples alongside their own signals to filter, decimate, or fan out to
+// multiple sinks without touching the driver directly.
+// Do not call Reset before or Shutdown after; Reset and Shutdown are called for you.
+func (hx711 *Hx711) Stream(ctx context.Context, opts StreamOptions) (<-chan Sample, <-chan error) {
+	numReadings := opts.NumReadings
+	if numReadings <= 0 {
+		numReadings = 11
+	}
+
+	samples := make(chan Sample)
+	errs := make(chan error)
+
+	go func() {
+		defer close(samples)
+		defer close(errs)
+
+		for {
+			err := hx711.Reset()
+			if err == nil {
+				break
+			}
+
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+		defer hx711.Shutdown()
+
+		for {
+			raw, weight, err := hx711.readWithMode(ctx, numReadings)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			sample := Sample{
+				Raw:       raw,
+				Weight:    weight,
+				Timestamp: time.Now(),
+			}
+
+			select {
+			case samples <- sample:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return samples, errs
+}